@@ -0,0 +1,56 @@
+package geocsv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRead_CustomDelimiterAndComment(t *testing.T) {
+	input := "# a comment line\nx;y\n1;2\n3;4\n"
+	gc, err := Read(strings.NewReader(input), GeoCSVOptions{
+		XField:    "x",
+		YField:    "y",
+		Delimiter: ';',
+		Comment:   '#',
+	})
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if gc.RowCount() != 2 {
+		t.Fatalf("got %d rows, want 2", gc.RowCount())
+	}
+}
+
+func TestRead_HeaderlessWithHeaderNames(t *testing.T) {
+	hasHeader := false
+	gc, err := Read(strings.NewReader("1,2\n3,4\n"), GeoCSVOptions{
+		XField:      "x",
+		YField:      "y",
+		HasHeader:   &hasHeader,
+		HeaderNames: []string{"x", "y"},
+	})
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if gc.RowCount() != 2 {
+		t.Fatalf("got %d rows, want 2 (first data row must not be dropped)", gc.RowCount())
+	}
+	fc := gc.ToFeatureCollection()
+	if len(fc.Features) != 2 {
+		t.Fatalf("got %d features, want 2", len(fc.Features))
+	}
+}
+
+func TestRead_FieldsPerRecordDisabled(t *testing.T) {
+	gc, err := Read(strings.NewReader("x,y,extra\n1,2\n3,4,5,6\n"), GeoCSVOptions{
+		XField:          "x",
+		YField:          "y",
+		FieldsPerRecord: -1,
+	})
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if gc.RowCount() != 2 {
+		t.Fatalf("got %d rows, want 2", gc.RowCount())
+	}
+}
@@ -0,0 +1,157 @@
+package geocsv
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// Well-known encoding names for GeoCSVOptions.Encoding and RegisterEncoding.
+const (
+	UTF8EncodingName     = "UTF-8"
+	UTF16LEEncodingName  = "UTF-16LE"
+	UTF16BEEncodingName  = "UTF-16BE"
+	GBKEncodingName      = "GBK"
+	GB18030EncodingName  = "GB18030"
+	Big5EncodingName     = "Big5"
+	ShiftJISEncodingName = "Shift-JIS"
+	Latin1EncodingName   = "Latin-1"
+)
+
+// sniffLength is how many bytes of the input are inspected when
+// GeoCSVOptions.Encoding is empty and the charset must be auto-detected.
+const sniffLength = 4096
+
+// encodingRegistry stores a decoder factory, not a shared instance, per
+// name: decoders like UTF-16's hold mutable per-call state behind a
+// pointer receiver, so handing the same instance to concurrent Read/Open
+// calls would race on it.
+var encodingRegistry = map[string]func() transform.Transformer{
+	UTF16LEEncodingName: func() transform.Transformer {
+		return unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewDecoder()
+	},
+	UTF16BEEncodingName:  func() transform.Transformer { return unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM).NewDecoder() },
+	GBKEncodingName:      func() transform.Transformer { return simplifiedchinese.GBK.NewDecoder() },
+	GB18030EncodingName:  func() transform.Transformer { return simplifiedchinese.GB18030.NewDecoder() },
+	Big5EncodingName:     func() transform.Transformer { return traditionalchinese.Big5.NewDecoder() },
+	ShiftJISEncodingName: func() transform.Transformer { return japanese.ShiftJIS.NewDecoder() },
+	Latin1EncodingName:   func() transform.Transformer { return charmap.ISO8859_1.NewDecoder() },
+}
+
+// RegisterEncoding makes a named charset decoder available to
+// GeoCSVOptions.Encoding, so callers can add codecs this package doesn't
+// ship with instead of forking it. Auto-detection only ever resolves to
+// a built-in name, so a new name is used only when Encoding names it;
+// registering a built-in name replaces that decoder everywhere. newDec
+// must return a fresh decoder on every call, since decoders may hold
+// mutable per-call state and concurrent Read/Open calls share the
+// registry. UTF-8 is built in and cannot be overridden.
+func RegisterEncoding(name string, newDec func() transform.Transformer) {
+	if name == UTF8EncodingName {
+		return
+	}
+	encodingRegistry[name] = newDec
+}
+
+// decoderFor resolves name to a fresh transformer from its registered
+// factory. "" and "UTF-8" both mean no transformation is needed.
+func decoderFor(name string) (transform.Transformer, bool) {
+	if name == "" || name == UTF8EncodingName {
+		return nil, true
+	}
+	newDec, ok := encodingRegistry[name]
+	if !ok {
+		return nil, false
+	}
+	return newDec(), true
+}
+
+var encoderRegistry = map[string]func() transform.Transformer{
+	UTF16LEEncodingName: func() transform.Transformer {
+		return unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewEncoder()
+	},
+	UTF16BEEncodingName:  func() transform.Transformer { return unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM).NewEncoder() },
+	GBKEncodingName:      func() transform.Transformer { return simplifiedchinese.GBK.NewEncoder() },
+	GB18030EncodingName:  func() transform.Transformer { return simplifiedchinese.GB18030.NewEncoder() },
+	Big5EncodingName:     func() transform.Transformer { return traditionalchinese.Big5.NewEncoder() },
+	ShiftJISEncodingName: func() transform.Transformer { return japanese.ShiftJIS.NewEncoder() },
+	Latin1EncodingName:   func() transform.Transformer { return charmap.ISO8859_1.NewEncoder() },
+}
+
+// encoderFor resolves name to an encoding.Encoder counterpart of
+// decoderFor, for Writer. "" and "UTF-8" both mean no transformation.
+func encoderFor(name string) (transform.Transformer, bool) {
+	if name == "" || name == UTF8EncodingName {
+		return nil, true
+	}
+	newEncoder, ok := encoderRegistry[name]
+	if !ok {
+		return nil, false
+	}
+	return newEncoder(), true
+}
+
+// detectEncoding sniffs sample for a BOM, then a UTF-8 validity check,
+// then whether it decodes as GBK without any invalid sequence, finally
+// falling back to Latin-1 (which never fails to decode a byte) so
+// detection always resolves to something usable instead of erroring out.
+func detectEncoding(sample []byte) string {
+	switch {
+	case bytes.HasPrefix(sample, []byte{0xEF, 0xBB, 0xBF}):
+		return UTF8EncodingName
+	case bytes.HasPrefix(sample, []byte{0xFF, 0xFE}):
+		return UTF16LEEncodingName
+	case bytes.HasPrefix(sample, []byte{0xFE, 0xFF}):
+		return UTF16BEEncodingName
+	}
+	if utf8.Valid(sample) {
+		return UTF8EncodingName
+	}
+	// The GBK decoder replaces invalid bytes with U+FFFD instead of
+	// failing, so look for replacements; atEOF is false because the
+	// sample may end in the middle of a character.
+	decoded := make([]byte, 3*len(sample))
+	n, _, err := simplifiedchinese.GBK.NewDecoder().Transform(decoded, sample, false)
+	if (err == nil || err == transform.ErrShortSrc) && !bytes.ContainsRune(decoded[:n], utf8.RuneError) {
+		return GBKEncodingName
+	}
+	return Latin1EncodingName
+}
+
+// newDecodingReader wraps r so its bytes are transcoded to UTF-8
+// according to name (or auto-detected from a sniff of the first
+// sniffLength bytes when name is empty), stripping a leading BOM.
+func newDecodingReader(r io.Reader, name string) (io.Reader, error) {
+	br := bufio.NewReaderSize(r, sniffLength)
+	sample, _ := br.Peek(sniffLength)
+
+	resolved := name
+	if resolved == "" {
+		resolved = detectEncoding(sample)
+	}
+
+	switch {
+	case bytes.HasPrefix(sample, []byte{0xEF, 0xBB, 0xBF}):
+		br.Discard(3)
+	case bytes.HasPrefix(sample, []byte{0xFF, 0xFE}), bytes.HasPrefix(sample, []byte{0xFE, 0xFF}):
+		br.Discard(2)
+	}
+
+	dec, ok := decoderFor(resolved)
+	if !ok {
+		return nil, fmt.Errorf("geocsv: unknown encoding %q", resolved)
+	}
+	if dec == nil {
+		return br, nil
+	}
+	return transform.NewReader(br, dec), nil
+}
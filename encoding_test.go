@@ -0,0 +1,136 @@
+package geocsv
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+func TestDetectEncoding(t *testing.T) {
+	if got := detectEncoding([]byte("hello")); got != UTF8EncodingName {
+		t.Errorf("detectEncoding(ascii) = %q, want %q", got, UTF8EncodingName)
+	}
+	if got := detectEncoding([]byte{0xEF, 0xBB, 0xBF, 'h', 'i'}); got != UTF8EncodingName {
+		t.Errorf("detectEncoding(utf8 BOM) = %q, want %q", got, UTF8EncodingName)
+	}
+	if got := detectEncoding([]byte{0xFF, 0xFE, 'h', 0}); got != UTF16LEEncodingName {
+		t.Errorf("detectEncoding(utf16le BOM) = %q, want %q", got, UTF16LEEncodingName)
+	}
+
+	gbkBytes, err := simplifiedchinese.GBK.NewEncoder().String("你好")
+	if err != nil {
+		t.Fatalf("encode GBK fixture: %v", err)
+	}
+	if got := detectEncoding([]byte(gbkBytes)); got != GBKEncodingName {
+		t.Errorf("detectEncoding(gbk) = %q, want %q", got, GBKEncodingName)
+	}
+}
+
+func TestNewDecodingReader_AutoDetectGBK(t *testing.T) {
+	gbkBytes, err := simplifiedchinese.GBK.NewEncoder().String("名称,值\n你好,1\n")
+	if err != nil {
+		t.Fatalf("encode GBK fixture: %v", err)
+	}
+
+	r, err := newDecodingReader(strings.NewReader(gbkBytes), "")
+	if err != nil {
+		t.Fatalf("newDecodingReader() error = %v", err)
+	}
+	decoded, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if !strings.Contains(string(decoded), "你好") {
+		t.Errorf("decoded output = %q, want it to contain 你好", decoded)
+	}
+}
+
+func TestNewDecodingReader_StripsUTF8BOM(t *testing.T) {
+	input := "\uFEFFx,y\n1,2\n"
+	r, err := newDecodingReader(strings.NewReader(input), UTF8EncodingName)
+	if err != nil {
+		t.Fatalf("newDecodingReader() error = %v", err)
+	}
+	decoded, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if strings.HasPrefix(string(decoded), "\uFEFF") {
+		t.Errorf("decoded output still has a BOM: %q", decoded)
+	}
+}
+
+func TestNewDecodingReader_UnknownEncoding(t *testing.T) {
+	if _, err := newDecodingReader(strings.NewReader("x"), "bogus"); err == nil {
+		t.Error("newDecodingReader() with an unregistered encoding should error")
+	}
+}
+
+func TestRegisterEncoding(t *testing.T) {
+	const name = "TEST-ENC"
+	RegisterEncoding(name, func() transform.Transformer { return simplifiedchinese.GBK.NewDecoder() })
+	if _, ok := decoderFor(name); !ok {
+		t.Errorf("decoderFor(%q) not found after RegisterEncoding", name)
+	}
+}
+
+// TestRead_ConcurrentUTF16 exercises Read with a stateful decoder
+// (UTF-16's) from many goroutines at once; run with -race, since a
+// shared decoder instance races on its internal state under concurrent
+// use.
+func TestRead_ConcurrentUTF16(t *testing.T) {
+	encoded, err := unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewEncoder().String("x,y,名称\n1,2,你好\n")
+	if err != nil {
+		t.Fatalf("encode UTF-16LE fixture: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			gc, err := Read(strings.NewReader(encoded), GeoCSVOptions{
+				XField:   "x",
+				YField:   "y",
+				Encoding: UTF16LEEncodingName,
+			})
+			if err != nil {
+				t.Errorf("Read() error = %v", err)
+				return
+			}
+			fc := gc.ToFeatureCollection()
+			if len(fc.Features) != 1 || fc.Features[0].Properties["名称"] != "你好" {
+				t.Errorf("decoded feature wrong: %#v", fc.Features)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestRead_ExplicitGBKEncoding(t *testing.T) {
+	gbkBytes, err := simplifiedchinese.GBK.NewEncoder().String("x,y,名称\n1,2,你好\n")
+	if err != nil {
+		t.Fatalf("encode GBK fixture: %v", err)
+	}
+
+	gc, err := Read(strings.NewReader(gbkBytes), GeoCSVOptions{
+		XField:   "x",
+		YField:   "y",
+		Encoding: GBKEncodingName,
+	})
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	fc := gc.ToFeatureCollection()
+	if len(fc.Features) != 1 {
+		t.Fatalf("got %d features, want 1", len(fc.Features))
+	}
+	if fc.Features[0].Properties["名称"] != "你好" {
+		t.Errorf("properties[名称] = %#v, want 你好", fc.Features[0].Properties["名称"])
+	}
+}
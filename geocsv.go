@@ -11,8 +11,6 @@ import (
 	"github.com/flywave/go-geom"
 	"github.com/flywave/go-geom/general"
 	"github.com/flywave/go-geom/wkt"
-
-	"golang.org/x/text/encoding/simplifiedchinese"
 )
 
 var defaultCoordValue = float64(-9999)
@@ -23,6 +21,8 @@ type GeoCSV struct {
 	headers []string
 	rows    [][]string
 	options GeoCSVOptions
+	schema  map[string]FieldType
+	index   *Index
 }
 
 type GeoCSVOptions struct {
@@ -30,6 +30,86 @@ type GeoCSVOptions struct {
 	XField   string
 	YField   string
 	WKTField string
+	// WKBField, when set, reads/writes the geometry as hex-encoded WKB
+	// instead of WKT, so geometries that don't round-trip through WKT
+	// (e.g. PostGIS ST_AsBinary exports) can be carried losslessly.
+	WKBField string
+	// Delimiter overrides the default comma field separator used by the
+	// underlying csv.Reader/csv.Writer.
+	Delimiter rune
+	// Encoding names the character set of the input/output ("UTF-8",
+	// "UTF-16LE", "UTF-16BE", "GBK", "GB18030", "Big5", "Shift-JIS",
+	// "Latin-1", or a name passed to RegisterEncoding). When empty, the
+	// reader auto-detects from a BOM/heuristic sniff of the input and
+	// the writer defaults to UTF-8. A name passed to RegisterEncoding
+	// applies to reading only: the writer has encoders for the built-in
+	// names and writes UTF-8 for any other name.
+	Encoding string
+	// CRS carries the coordinate reference system (e.g. "EPSG:4326") as
+	// metadata alongside the data; it is not interpreted by this package.
+	CRS string
+	// InferTypes, when true and Schema is nil, scans every buffered row
+	// to infer a FieldType per column (int64/float64/bool/time.Time,
+	// falling back to string) before building features.
+	InferTypes bool
+	// Schema overrides inference with an explicit FieldType per column
+	// name; columns not listed are left as strings.
+	Schema map[string]FieldType
+	// GeometryField names a column decoded via GeometryFormat, in
+	// addition to (and taking priority over) WKTField. Use this for
+	// EWKT, WKB, or GeoJSON geometry columns.
+	GeometryField string
+	// GeometryFormat selects how GeometryField is decoded; it defaults
+	// to FormatWKT.
+	GeometryFormat GeometryFormat
+	// Comment, when non-zero, marks lines beginning with that rune as
+	// comments to be skipped, as csv.Reader.Comment does.
+	Comment rune
+	// LazyQuotes relaxes csv.Reader's quoting rules, allowing a quote to
+	// appear in an unquoted field and a non-doubled quote to appear in a
+	// quoted field; useful for WKT/WKB columns exported by tools that
+	// don't escape quotes strictly.
+	LazyQuotes bool
+	// TrimLeadingSpace removes leading whitespace from a field before
+	// parsing, as csv.Reader.TrimLeadingSpace does.
+	TrimLeadingSpace bool
+	// FieldsPerRecord controls csv.Reader.FieldsPerRecord: 0 (the
+	// default) requires every record to have as many fields as the
+	// header, a positive value requires that exact count, and a
+	// negative value disables the check entirely.
+	FieldsPerRecord int
+	// HasHeader, when explicitly set to false, treats the first row as
+	// data rather than a header; HeaderNames must then supply the
+	// column names. It defaults to true.
+	HasHeader *bool
+	// HeaderNames supplies column names for headerless input (see
+	// HasHeader); ignored otherwise.
+	HeaderNames []string
+}
+
+// hasHeader reports whether the input's first row is a header, honoring
+// options.HasHeader's default of true when left nil.
+func (options GeoCSVOptions) hasHeader() bool {
+	return options.HasHeader == nil || *options.HasHeader
+}
+
+// newCSVReader builds a csv.Reader over r configured with options'
+// dialect settings (Delimiter, Comment, LazyQuotes, TrimLeadingSpace,
+// FieldsPerRecord), shared by readRecords and the streaming iterator.
+func newCSVReader(r io.Reader, options GeoCSVOptions) *csv.Reader {
+	reader := csv.NewReader(r)
+	if options.Delimiter != 0 {
+		reader.Comma = options.Delimiter
+	}
+	if options.Comment != 0 {
+		reader.Comment = options.Comment
+	}
+	reader.LazyQuotes = options.LazyQuotes
+	reader.TrimLeadingSpace = options.TrimLeadingSpace
+	if options.FieldsPerRecord != 0 {
+		reader.FieldsPerRecord = options.FieldsPerRecord
+	}
+	return reader
 }
 
 func NewGeoCSV() (gc *GeoCSV) {
@@ -49,9 +129,15 @@ func (gc *GeoCSV) readRecords() (err error) {
 		err = errors.New("file is nil")
 		return
 	}
-	headerRead := false
-	gbkDecoder := simplifiedchinese.GBK.NewDecoder()
-	reader := csv.NewReader(gc.getReader())
+	decoded, err := newDecodingReader(gc.getReader(), gc.options.Encoding)
+	if err != nil {
+		return
+	}
+	headerRead := !gc.options.hasHeader()
+	if headerRead {
+		gc.headers = gc.options.HeaderNames
+	}
+	reader := newCSVReader(decoded, gc.options)
 	for {
 		record, readErr := reader.Read()
 		if readErr == io.EOF {
@@ -61,40 +147,30 @@ func (gc *GeoCSV) readRecords() (err error) {
 			err = readErr
 			return
 		}
-		encodeValues := make([]string, 0, len(record))
-		for _, value := range record {
-			var encodeValue string
-			coding := GetStringEncoding(value)
-			switch coding {
-			case UTF8:
-				encodeValue = value
-			case GBK:
-				encodingString, _ := gbkDecoder.Bytes([]byte(value))
-				encodeValue = string(encodingString)
-			default:
-				if encodingString, decodeError := gbkDecoder.Bytes([]byte(value)); decodeError == nil {
-					encodeValue = string(encodingString)
-				} else {
-					err = errors.New("file encoding is not supported")
-					return
-				}
-			}
-			encodeValue = strings.TrimSpace(encodeValue)
-
-			encodeValue = strings.ReplaceAll(encodeValue, "\uFEFF", "")
-			encodeValue = strings.TrimSpace(encodeValue)
-			encodeValues = append(encodeValues, encodeValue)
-		}
+		values := trimRecord(record)
 		if !headerRead {
 			headerRead = true
-			gc.headers = encodeValues
+			gc.headers = values
 		} else {
-			gc.rows = append(gc.rows, encodeValues)
+			gc.rows = append(gc.rows, values)
 		}
 	}
+	if gc.options.Schema != nil {
+		gc.schema = gc.options.Schema
+	} else if gc.options.InferTypes {
+		gc.schema = inferSchema(gc.headers, gc.rows)
+	}
 	return
 }
 
+func trimRecord(record []string) []string {
+	values := make([]string, len(record))
+	for i, value := range record {
+		values[i] = strings.TrimSpace(value)
+	}
+	return values
+}
+
 func (gc *GeoCSV) Valid() bool {
 	if len(gc.headers) == 0 || gc.RowCount() == 0 {
 		return false
@@ -127,34 +203,61 @@ func (gc *GeoCSV) RowCount() int {
 
 func (gc *GeoCSV) Feature(i int) *geom.Feature {
 	if i < gc.RowCount() {
-		var (
-			lng      = defaultCoordValue
-			lat      = defaultCoordValue
-			geometry geom.Geometry
-		)
-		properties := map[string]interface{}{}
-
-		for j, cell := range gc.rows[i] {
-			fieldName := gc.headers[j]
-			if len(gc.options.WKTField) > 0 && fieldName == gc.options.WKTField {
-				if wktGeometry, _, wktError := wkt.DecodeWKT([]byte(cell)); wktError == nil {
-					geometry = general.GeometryDataAsGeometry(wktGeometry)
+		return featureFromRow(gc.headers, gc.rows[i], gc.options, gc.schema)
+	}
+	return nil
+}
+
+// featureFromRow decodes a single CSV row into a feature, applying the
+// same geometry/property rules used by Feature, ToFeatureCollection, and
+// FeatureIterator. schema is nil unless InferTypes or Schema is set, in
+// which case property values are parsed into the typed Go value for
+// their column instead of being left as strings.
+func featureFromRow(headers []string, row []string, options GeoCSVOptions, schema map[string]FieldType) *geom.Feature {
+	var (
+		lng      = defaultCoordValue
+		lat      = defaultCoordValue
+		geometry geom.Geometry
+	)
+	properties := map[string]interface{}{}
+	fromGeometryField := false
+
+	for j, cell := range row {
+		if j >= len(headers) {
+			continue
+		}
+		fieldName := headers[j]
+		switch {
+		case len(options.GeometryField) > 0 && fieldName == options.GeometryField:
+			if g, srid, gerr := decodeGeometryColumn(cell, options.GeometryFormat); gerr == nil {
+				geometry = g
+				fromGeometryField = true
+				if srid != 0 {
+					properties[SRIDProperty] = srid
 				}
-			} else if len(gc.options.XField) > 0 && fieldName == gc.options.XField {
-				lng, _ = strconv.ParseFloat(cell, 64)
-			} else if len(gc.options.YField) > 0 && fieldName == gc.options.YField {
-				lat, _ = strconv.ParseFloat(cell, 64)
 			}
-			properties[fieldName] = cell
-		}
-		if geometry == nil && lng != defaultCoordValue && lat != defaultCoordValue {
-			geometry = general.NewPoint([]float64{lng, lat})
-		}
-		if geometry != nil {
-			feature := geom.NewFeature(geometry)
-			feature.Properties = properties
-			return feature
+		case len(options.WKTField) > 0 && fieldName == options.WKTField:
+			if wktGeometry, _, wktError := wkt.DecodeWKT([]byte(cell)); wktError == nil && !fromGeometryField {
+				geometry = general.GeometryDataAsGeometry(wktGeometry)
+			}
+		case len(options.WKBField) > 0 && fieldName == options.WKBField:
+			if wkbGeometry, _, wkbError := decodeWKBHexGeometry(cell); wkbError == nil && !fromGeometryField {
+				geometry = wkbGeometry
+			}
+		case len(options.XField) > 0 && fieldName == options.XField:
+			lng, _ = strconv.ParseFloat(cell, 64)
+		case len(options.YField) > 0 && fieldName == options.YField:
+			lat, _ = strconv.ParseFloat(cell, 64)
 		}
+		properties[fieldName] = cellValue(cell, fieldName, schema)
+	}
+	if geometry == nil && lng != defaultCoordValue && lat != defaultCoordValue {
+		geometry = general.NewPoint([]float64{lng, lat})
+	}
+	if geometry != nil {
+		feature := geom.NewFeature(geometry)
+		feature.Properties = properties
+		return feature
 	}
 	return nil
 }
@@ -185,32 +288,7 @@ func Read(reader io.Reader, options GeoCSVOptions) (gc *GeoCSV, err error) {
 func (gc *GeoCSV) ToFeatureCollection() (features *geom.FeatureCollection) {
 	features = geom.NewFeatureCollection()
 	for _, row := range gc.rows {
-		var (
-			lng      = defaultCoordValue
-			lat      = defaultCoordValue
-			geometry geom.Geometry
-		)
-		properties := map[string]interface{}{}
-
-		for j, cell := range row {
-			fieldName := gc.headers[j]
-			if len(gc.options.WKTField) > 0 && fieldName == gc.options.WKTField {
-				if wktGeometry, _, wktError := wkt.DecodeWKT([]byte(cell)); wktError == nil {
-					geometry = general.GeometryDataAsGeometry(wktGeometry)
-				}
-			} else if len(gc.options.XField) > 0 && fieldName == gc.options.XField {
-				lng, _ = strconv.ParseFloat(cell, 64)
-			} else if len(gc.options.YField) > 0 && fieldName == gc.options.YField {
-				lat, _ = strconv.ParseFloat(cell, 64)
-			}
-			properties[fieldName] = cell
-		}
-		if geometry == nil && lng != defaultCoordValue && lat != defaultCoordValue {
-			geometry = general.NewPoint([]float64{lng, lat})
-		}
-		if geometry != nil {
-			feature := geom.NewFeature(geometry)
-			feature.Properties = properties
+		if feature := featureFromRow(gc.headers, row, gc.options, gc.schema); feature != nil {
 			features.Features = append(features.Features, feature)
 		}
 	}
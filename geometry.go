@@ -0,0 +1,162 @@
+package geocsv
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/flywave/go-geom"
+	"github.com/flywave/go-geom/general"
+	"github.com/flywave/go-geom/wkb"
+	"github.com/flywave/go-geom/wkt"
+)
+
+// GeometryFormat selects how GeoCSVOptions.GeometryField's column is
+// decoded.
+type GeometryFormat int
+
+const (
+	FormatWKT GeometryFormat = iota
+	FormatEWKT
+	FormatWKBHex
+	FormatWKBBase64
+	FormatGeoJSON
+)
+
+// SRIDProperty is the feature property key the decoded SRID is recorded
+// under for formats that carry one: EWKT's "SRID=...;" prefix, or a
+// non-zero SRID reported by the WKB decoder for WKB hex/base64 input.
+const SRIDProperty = "srid"
+
+// geometryDecoder parses a column's raw text into a geometry, returning
+// the SRID it carried, or 0 when the format doesn't encode one.
+type geometryDecoder func(value string) (geom.Geometry, int, error)
+
+var geometryDecoders = map[GeometryFormat]geometryDecoder{
+	FormatWKT:       decodeWKTGeometry,
+	FormatEWKT:      decodeEWKTGeometry,
+	FormatWKBHex:    decodeWKBHexGeometry,
+	FormatWKBBase64: decodeWKBBase64Geometry,
+	FormatGeoJSON:   decodeGeoJSONGeometry,
+}
+
+// decodeGeometryColumn decodes cell using format, the pluggable
+// counterpart to the WKTField-only decoding GeoCSV has always done.
+func decodeGeometryColumn(cell string, format GeometryFormat) (geom.Geometry, int, error) {
+	decode, ok := geometryDecoders[format]
+	if !ok {
+		return nil, 0, fmt.Errorf("geocsv: unsupported geometry format %d", format)
+	}
+	return decode(cell)
+}
+
+func decodeWKTGeometry(value string) (geom.Geometry, int, error) {
+	data, _, err := wkt.DecodeWKT([]byte(value))
+	if err != nil {
+		return nil, 0, err
+	}
+	return general.GeometryDataAsGeometry(data), 0, nil
+}
+
+// decodeEWKTGeometry strips a PostGIS "SRID=4326;..." prefix before
+// parsing the remainder as WKT, returning the SRID it found.
+func decodeEWKTGeometry(value string) (geom.Geometry, int, error) {
+	srid := 0
+	text := value
+	if strings.HasPrefix(text, "SRID=") {
+		if idx := strings.IndexByte(text, ';'); idx > 0 {
+			if parsed, err := strconv.Atoi(text[len("SRID="):idx]); err == nil {
+				srid = parsed
+			}
+			text = text[idx+1:]
+		}
+	}
+	geometry, _, err := decodeWKTGeometry(text)
+	if err != nil {
+		return nil, 0, err
+	}
+	return geometry, srid, nil
+}
+
+func decodeWKBHexGeometry(value string) (geom.Geometry, int, error) {
+	data, err := hex.DecodeString(value)
+	if err != nil {
+		return nil, 0, err
+	}
+	return decodeWKBBytes(data)
+}
+
+func decodeWKBBase64Geometry(value string) (geom.Geometry, int, error) {
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, 0, err
+	}
+	return decodeWKBBytes(data)
+}
+
+func decodeWKBBytes(data []byte) (geom.Geometry, int, error) {
+	geometryData, srid, err := wkb.DecodeWKB(bytes.NewReader(data))
+	if err != nil {
+		return nil, 0, err
+	}
+	return general.GeometryDataAsGeometry(geometryData), int(srid), nil
+}
+
+// geoJSONGeometry mirrors the minimal GeoJSON Geometry object so its
+// coordinates can be unmarshalled per-type below.
+type geoJSONGeometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+func decodeGeoJSONGeometry(value string) (geom.Geometry, int, error) {
+	var g geoJSONGeometry
+	if err := json.Unmarshal([]byte(value), &g); err != nil {
+		return nil, 0, err
+	}
+	switch g.Type {
+	case "Point":
+		var coords []float64
+		if err := json.Unmarshal(g.Coordinates, &coords); err != nil {
+			return nil, 0, err
+		}
+		return general.NewPoint(coords), 0, nil
+	case "LineString":
+		var coords [][]float64
+		if err := json.Unmarshal(g.Coordinates, &coords); err != nil {
+			return nil, 0, err
+		}
+		return general.NewLineString(coords), 0, nil
+	case "Polygon":
+		var coords [][][]float64
+		if err := json.Unmarshal(g.Coordinates, &coords); err != nil {
+			return nil, 0, err
+		}
+		return general.NewPolygon(coords), 0, nil
+	case "MultiPoint":
+		var coords [][]float64
+		if err := json.Unmarshal(g.Coordinates, &coords); err != nil {
+			return nil, 0, err
+		}
+		return general.NewMultiPoint(coords), 0, nil
+	case "MultiLineString":
+		var coords [][][]float64
+		if err := json.Unmarshal(g.Coordinates, &coords); err != nil {
+			return nil, 0, err
+		}
+		return general.NewMultiLineString(coords), 0, nil
+	case "MultiPolygon":
+		var coords [][][][]float64
+		if err := json.Unmarshal(g.Coordinates, &coords); err != nil {
+			return nil, 0, err
+		}
+		return general.NewMultiPolygon(coords), 0, nil
+	default:
+		return nil, 0, errors.New("geocsv: unsupported GeoJSON geometry type " + g.Type)
+	}
+}
@@ -0,0 +1,63 @@
+package geocsv
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/flywave/go-geom"
+	"github.com/flywave/go-geom/general"
+	"github.com/flywave/go-geom/wkb"
+)
+
+func TestDecodeGeometryColumn_EWKT(t *testing.T) {
+	geometry, srid, err := decodeGeometryColumn("SRID=4326;POINT(2 49)", FormatEWKT)
+	if err != nil {
+		t.Fatalf("decodeGeometryColumn() error = %v", err)
+	}
+	if srid != 4326 {
+		t.Errorf("srid = %d, want 4326", srid)
+	}
+	point := geometry.(geom.Point)
+	if point.X() != 2 || point.Y() != 49 {
+		t.Errorf("point = (%v, %v), want (2, 49)", point.X(), point.Y())
+	}
+}
+
+func TestDecodeGeometryColumn_WKBHexAndBase64(t *testing.T) {
+	var buf bytes.Buffer
+	gd := geom.NewGeometryData(general.NewPoint([]float64{2, 49}))
+	if err := wkb.EncodeWKB(gd, nil, &buf); err != nil {
+		t.Fatalf("EncodeWKB() error = %v", err)
+	}
+
+	hexGeom, _, err := decodeGeometryColumn(hex.EncodeToString(buf.Bytes()), FormatWKBHex)
+	if err != nil {
+		t.Fatalf("decodeGeometryColumn(hex) error = %v", err)
+	}
+	point := hexGeom.(geom.Point)
+	if point.X() != 2 || point.Y() != 49 {
+		t.Errorf("hex point = (%v, %v), want (2, 49)", point.X(), point.Y())
+	}
+
+	b64Geom, _, err := decodeGeometryColumn(base64.StdEncoding.EncodeToString(buf.Bytes()), FormatWKBBase64)
+	if err != nil {
+		t.Fatalf("decodeGeometryColumn(base64) error = %v", err)
+	}
+	point = b64Geom.(geom.Point)
+	if point.X() != 2 || point.Y() != 49 {
+		t.Errorf("base64 point = (%v, %v), want (2, 49)", point.X(), point.Y())
+	}
+}
+
+func TestDecodeGeometryColumn_GeoJSON(t *testing.T) {
+	geometry, _, err := decodeGeometryColumn(`{"type":"Point","coordinates":[2,49]}`, FormatGeoJSON)
+	if err != nil {
+		t.Fatalf("decodeGeometryColumn() error = %v", err)
+	}
+	point := geometry.(geom.Point)
+	if point.X() != 2 || point.Y() != 49 {
+		t.Errorf("point = (%v, %v), want (2, 49)", point.X(), point.Y())
+	}
+}
@@ -0,0 +1,261 @@
+package geocsv
+
+import (
+	"container/heap"
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/flywave/go-geom"
+)
+
+// leafCapacity is the maximum number of entries packed into a leaf node
+// (M in the STR bulk-loading algorithm).
+const leafCapacity = 16
+
+// Index is a static R-tree built over a GeoCSV's decoded features via
+// STR (sort-tile-recursive) bulk loading, giving O(N log N) construction
+// and good query fanout compared to inserting one feature at a time.
+type Index struct {
+	root    *indexNode
+	entries int
+}
+
+type indexEntry struct {
+	bbox    [4]float64 // minX, minY, maxX, maxY
+	feature *geom.Feature
+}
+
+type indexNode struct {
+	bbox     [4]float64
+	children []*indexNode
+	entries  []indexEntry
+	leaf     bool
+}
+
+// BuildIndex decodes every row into a feature, computes each feature's
+// geometry bounding box, and bulk-loads an R-tree over them. Features
+// whose geometry exposes no usable bounding box (see geometryBounds) are
+// left out of the index.
+func (gc *GeoCSV) BuildIndex() error {
+	if gc.RowCount() == 0 {
+		return errors.New("geocsv: no rows to index")
+	}
+	entries := make([]indexEntry, 0, gc.RowCount())
+	for _, row := range gc.rows {
+		feature := featureFromRow(gc.headers, row, gc.options, gc.schema)
+		if feature == nil {
+			continue
+		}
+		bbox, ok := geometryBounds(feature.Geometry)
+		if !ok {
+			continue
+		}
+		entries = append(entries, indexEntry{bbox: bbox, feature: feature})
+	}
+	if len(entries) == 0 {
+		return errors.New("geocsv: no indexable geometries")
+	}
+	gc.index = &Index{root: strPack(entries), entries: len(entries)}
+	return nil
+}
+
+// geometryBounds computes a geometry's axis-aligned bounding box via the
+// library's own BoundingBoxFromGeometry, which covers every geometry kind
+// (Point/MultiPoint/LineString/MultiLine/Polygon/MultiPolygon), not just
+// points.
+func geometryBounds(g geom.Geometry) ([4]float64, bool) {
+	if g == nil {
+		return [4]float64{}, false
+	}
+	bbox := geom.BoundingBoxFromGeometry(g)
+	if bbox == nil {
+		return [4]float64{}, false
+	}
+	return [4]float64{bbox[0][0], bbox[0][1], bbox[1][0], bbox[1][1]}, true
+}
+
+// strPack bulk-loads entries into an R-tree: leaves of size leafCapacity
+// are packed by sorting into ceil(sqrt(leafCount)) vertical strips and
+// sorting each strip by Y, then the resulting nodes are packed the same
+// way, recursing until a single root remains.
+func strPack(entries []indexEntry) *indexNode {
+	nodes := make([]*indexNode, len(entries))
+	for i, e := range entries {
+		nodes[i] = &indexNode{bbox: e.bbox, leaf: true, entries: []indexEntry{e}}
+	}
+	// Tile the entries into leaves of up to leafCapacity entries each.
+	nodes = strPackLevel(nodes)
+	for _, node := range nodes {
+		for _, child := range node.children {
+			node.entries = append(node.entries, child.entries...)
+		}
+		node.children = nil
+		node.leaf = true
+	}
+	for len(nodes) > 1 {
+		nodes = strPackLevel(nodes)
+	}
+	return nodes[0]
+}
+
+func strPackLevel(nodes []*indexNode) []*indexNode {
+	n := len(nodes)
+	leafCount := int(math.Ceil(float64(n) / float64(leafCapacity)))
+	stripCount := int(math.Ceil(math.Sqrt(float64(leafCount))))
+	if stripCount < 1 {
+		stripCount = 1
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return centerX(nodes[i].bbox) < centerX(nodes[j].bbox)
+	})
+	stripSize := int(math.Ceil(float64(n) / float64(stripCount)))
+
+	var packed []*indexNode
+	for i := 0; i < n; i += stripSize {
+		end := i + stripSize
+		if end > n {
+			end = n
+		}
+		strip := nodes[i:end]
+		sort.Slice(strip, func(a, b int) bool {
+			return centerY(strip[a].bbox) < centerY(strip[b].bbox)
+		})
+		for j := 0; j < len(strip); j += leafCapacity {
+			k := j + leafCapacity
+			if k > len(strip) {
+				k = len(strip)
+			}
+			packed = append(packed, newParentNode(strip[j:k]))
+		}
+	}
+	return packed
+}
+
+func newParentNode(children []*indexNode) *indexNode {
+	node := &indexNode{children: append([]*indexNode{}, children...)}
+	node.bbox = children[0].bbox
+	for _, child := range children[1:] {
+		node.bbox = unionBBox(node.bbox, child.bbox)
+	}
+	return node
+}
+
+func centerX(b [4]float64) float64 { return (b[0] + b[2]) / 2 }
+func centerY(b [4]float64) float64 { return (b[1] + b[3]) / 2 }
+
+func unionBBox(a, b [4]float64) [4]float64 {
+	return [4]float64{
+		math.Min(a[0], b[0]),
+		math.Min(a[1], b[1]),
+		math.Max(a[2], b[2]),
+		math.Max(a[3], b[3]),
+	}
+}
+
+func bboxIntersects(a, b [4]float64) bool {
+	return a[0] <= b[2] && a[2] >= b[0] && a[1] <= b[3] && a[3] >= b[1]
+}
+
+// Intersects returns every indexed feature whose bounding box overlaps
+// bbox (minX, minY, maxX, maxY).
+func (gc *GeoCSV) Intersects(bbox [4]float64) []*geom.Feature {
+	if gc.index == nil {
+		return nil
+	}
+	var results []*geom.Feature
+	var walk func(node *indexNode)
+	walk = func(node *indexNode) {
+		if !bboxIntersects(node.bbox, bbox) {
+			return
+		}
+		if node.leaf {
+			for _, e := range node.entries {
+				if bboxIntersects(e.bbox, bbox) {
+					results = append(results, e.feature)
+				}
+			}
+			return
+		}
+		for _, child := range node.children {
+			walk(child)
+		}
+	}
+	walk(gc.index.root)
+	return results
+}
+
+// Contains returns every indexed feature whose bounding box contains pt.
+func (gc *GeoCSV) Contains(pt [2]float64) []*geom.Feature {
+	return gc.Intersects([4]float64{pt[0], pt[1], pt[0], pt[1]})
+}
+
+// nnItem is a candidate in NearestN's best-first search: either an
+// unexpanded node or a concrete leaf entry, ordered by distance from the
+// query point.
+type nnItem struct {
+	dist float64
+	node *indexNode
+	leaf *indexEntry
+}
+
+type nnHeap []nnItem
+
+func (h nnHeap) Len() int            { return len(h) }
+func (h nnHeap) Less(i, j int) bool  { return h[i].dist < h[j].dist }
+func (h nnHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *nnHeap) Push(x interface{}) { *h = append(*h, x.(nnItem)) }
+func (h *nnHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+func bboxMinDist(b [4]float64, pt [2]float64) float64 {
+	dx := 0.0
+	if pt[0] < b[0] {
+		dx = b[0] - pt[0]
+	} else if pt[0] > b[2] {
+		dx = pt[0] - b[2]
+	}
+	dy := 0.0
+	if pt[1] < b[1] {
+		dy = b[1] - pt[1]
+	} else if pt[1] > b[3] {
+		dy = pt[1] - b[3]
+	}
+	return math.Hypot(dx, dy)
+}
+
+// NearestN returns up to n indexed features nearest to pt, using the
+// incremental best-first search over node bounding-box distances so
+// only as much of the tree as needed is expanded.
+func (gc *GeoCSV) NearestN(pt [2]float64, n int) []*geom.Feature {
+	if gc.index == nil || n <= 0 {
+		return nil
+	}
+	h := &nnHeap{{dist: bboxMinDist(gc.index.root.bbox, pt), node: gc.index.root}}
+	heap.Init(h)
+
+	var results []*geom.Feature
+	for h.Len() > 0 && len(results) < n {
+		item := heap.Pop(h).(nnItem)
+		if item.leaf != nil {
+			results = append(results, item.leaf.feature)
+			continue
+		}
+		if item.node.leaf {
+			for i := range item.node.entries {
+				e := &item.node.entries[i]
+				heap.Push(h, nnItem{dist: bboxMinDist(e.bbox, pt), leaf: e})
+			}
+			continue
+		}
+		for _, child := range item.node.children {
+			heap.Push(h, nnItem{dist: bboxMinDist(child.bbox, pt), node: child})
+		}
+	}
+	return results
+}
@@ -0,0 +1,111 @@
+package geocsv
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func csvOfPoints(n int) string {
+	var b strings.Builder
+	b.WriteString("x,y\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "%d,%d\n", i, i)
+	}
+	return b.String()
+}
+
+func TestBuildIndex_Intersects(t *testing.T) {
+	gc, err := Read(strings.NewReader(csvOfPoints(50)), GeoCSVOptions{XField: "x", YField: "y"})
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if err := gc.BuildIndex(); err != nil {
+		t.Fatalf("BuildIndex() error = %v", err)
+	}
+
+	results := gc.Intersects([4]float64{10, 10, 15, 15})
+	if len(results) != 6 {
+		t.Fatalf("got %d results, want 6", len(results))
+	}
+	for _, f := range results {
+		point := f.Geometry.(interface{ X() float64 })
+		if point.X() < 10 || point.X() > 15 {
+			t.Errorf("result outside query bbox: x = %v", point.X())
+		}
+	}
+}
+
+func TestBuildIndex_Contains(t *testing.T) {
+	gc, err := Read(strings.NewReader(csvOfPoints(20)), GeoCSVOptions{XField: "x", YField: "y"})
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if err := gc.BuildIndex(); err != nil {
+		t.Fatalf("BuildIndex() error = %v", err)
+	}
+
+	results := gc.Contains([2]float64{5, 5})
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+}
+
+func TestBuildIndex_NearestN(t *testing.T) {
+	gc, err := Read(strings.NewReader(csvOfPoints(20)), GeoCSVOptions{XField: "x", YField: "y"})
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if err := gc.BuildIndex(); err != nil {
+		t.Fatalf("BuildIndex() error = %v", err)
+	}
+
+	results := gc.NearestN([2]float64{9.4, 9.4}, 3)
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+	point := results[0].Geometry.(interface{ X() float64 })
+	if point.X() != 9 {
+		t.Errorf("nearest point x = %v, want 9", point.X())
+	}
+}
+
+func TestBuildIndex_NoRows(t *testing.T) {
+	gc, err := Read(strings.NewReader("x,y\n"), GeoCSVOptions{XField: "x", YField: "y"})
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if err := gc.BuildIndex(); err == nil {
+		t.Error("BuildIndex() on an empty GeoCSV should return an error")
+	}
+}
+
+func TestBuildIndex_PolygonsAndLines(t *testing.T) {
+	input := "id,wkt\n" +
+		"1,\"POLYGON((0 0, 0 10, 10 10, 10 0, 0 0))\"\n" +
+		"2,\"POLYGON((20 20, 20 30, 30 30, 30 20, 20 20))\"\n" +
+		"3,\"LINESTRING(100 100, 110 110)\"\n"
+	gc, err := Read(strings.NewReader(input), GeoCSVOptions{WKTField: "wkt"})
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if err := gc.BuildIndex(); err != nil {
+		t.Fatalf("BuildIndex() error = %v", err)
+	}
+
+	results := gc.Intersects([4]float64{5, 5, 5, 5})
+	if len(results) != 1 {
+		t.Fatalf("got %d results for the polygon query, want 1 (polygons/lines must be indexed)", len(results))
+	}
+	if results[0].Properties["id"] != "1" {
+		t.Errorf("results[0].id = %v, want 1", results[0].Properties["id"])
+	}
+
+	results = gc.Intersects([4]float64{100, 100, 110, 110})
+	if len(results) != 1 {
+		t.Fatalf("got %d results for the line query, want 1", len(results))
+	}
+	if results[0].Properties["id"] != "3" {
+		t.Errorf("results[0].id = %v, want 3", results[0].Properties["id"])
+	}
+}
@@ -0,0 +1,140 @@
+package geocsv
+
+import (
+	"encoding/csv"
+	"io"
+	"os"
+
+	"github.com/flywave/go-geom"
+)
+
+// FeatureIterator streams features from a CSV source one row at a time,
+// decoding each record lazily instead of buffering the whole file like
+// GeoCSV does.
+type FeatureIterator struct {
+	file    *os.File
+	reader  *csv.Reader
+	options GeoCSVOptions
+	headers []string
+	schema  map[string]FieldType
+	seen    map[string]bool
+	feature *geom.Feature
+	err     error
+	closed  bool
+}
+
+// OpenFile opens filePath and returns a FeatureIterator over its rows.
+// The caller must call Close when done.
+func OpenFile(filePath string, options GeoCSVOptions) (it *FeatureIterator, err error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	it, err = Open(file, options)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	it.file = file
+	return it, nil
+}
+
+// Open returns a FeatureIterator over reader's rows without buffering
+// them. The header row (when HasHeader is not false) is read
+// immediately, so a missing or malformed header is returned as Open's
+// error before the caller consumes anything.
+func Open(reader io.Reader, options GeoCSVOptions) (it *FeatureIterator, err error) {
+	decoded, err := newDecodingReader(reader, options.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	csvReader := newCSVReader(decoded, options)
+	it = &FeatureIterator{
+		reader:  csvReader,
+		options: options,
+	}
+	if options.hasHeader() {
+		record, readErr := csvReader.Read()
+		if readErr != nil {
+			return nil, readErr
+		}
+		it.headers = trimRecord(record)
+	} else {
+		it.headers = options.HeaderNames
+	}
+	if options.Schema != nil {
+		it.schema = options.Schema
+	} else if options.InferTypes {
+		it.schema = make(map[string]FieldType, len(it.headers))
+		it.seen = make(map[string]bool, len(it.headers))
+	}
+	return it, nil
+}
+
+// updateSchema promotes the running per-column FieldType with the value
+// just read, the streaming single-pass counterpart of inferSchema's
+// two-pass scan. Features already returned by Feature keep whatever
+// type was in effect when they were decoded.
+func (it *FeatureIterator) updateSchema(row []string) {
+	if it.options.Schema != nil || !it.options.InferTypes {
+		return
+	}
+	for j, cell := range row {
+		if j >= len(it.headers) {
+			continue
+		}
+		name := it.headers[j]
+		t := inferFieldType(cell)
+		if !it.seen[name] {
+			it.schema[name] = t
+			it.seen[name] = true
+		} else {
+			it.schema[name] = promoteFieldType(it.schema[name], t)
+		}
+	}
+}
+
+// Next advances the iterator to the next row with a decodable geometry,
+// skipping rows that don't carry one. It returns false at EOF or on
+// error; callers should check Err afterwards.
+func (it *FeatureIterator) Next() bool {
+	if it.closed || it.err != nil {
+		return false
+	}
+	for {
+		record, readErr := it.reader.Read()
+		if readErr == io.EOF {
+			return false
+		}
+		if readErr != nil {
+			it.err = readErr
+			return false
+		}
+		row := trimRecord(record)
+		it.updateSchema(row)
+		if feature := featureFromRow(it.headers, row, it.options, it.schema); feature != nil {
+			it.feature = feature
+			return true
+		}
+	}
+}
+
+// Feature returns the feature decoded by the most recent call to Next.
+func (it *FeatureIterator) Feature() *geom.Feature {
+	return it.feature
+}
+
+// Err returns the first error encountered by Next, if any.
+func (it *FeatureIterator) Err() error {
+	return it.err
+}
+
+// Close releases the underlying file, if the iterator was opened via
+// OpenFile.
+func (it *FeatureIterator) Close() error {
+	it.closed = true
+	if it.file != nil {
+		return it.file.Close()
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package geocsv
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/flywave/go-geom"
+)
+
+func TestOpen_Iterates(t *testing.T) {
+	it, err := Open(strings.NewReader("x,y\n1,2\n3,4\n"), GeoCSVOptions{XField: "x", YField: "y"})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer it.Close()
+
+	var points [][2]float64
+	for it.Next() {
+		point := it.Feature().Geometry.(geom.Point)
+		points = append(points, [2]float64{point.X(), point.Y()})
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+	want := [][2]float64{{1, 2}, {3, 4}}
+	if len(points) != len(want) {
+		t.Fatalf("got %d features, want %d", len(points), len(want))
+	}
+	for i, p := range want {
+		if points[i] != p {
+			t.Errorf("feature %d = %v, want %v", i, points[i], p)
+		}
+	}
+}
+
+func TestOpenFile(t *testing.T) {
+	it, err := OpenFile("./test1.csv", GeoCSVOptions{XField: "x", YField: "y"})
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	defer it.Close()
+
+	count := 0
+	for it.Next() {
+		count++
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+	if count != 4 {
+		t.Errorf("got %d features, want 4", count)
+	}
+}
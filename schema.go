@@ -0,0 +1,112 @@
+package geocsv
+
+import (
+	"strconv"
+	"time"
+)
+
+// FieldType identifies the Go type a CSV column's values are parsed into
+// when GeoCSVOptions.InferTypes or GeoCSVOptions.Schema requests typed
+// properties instead of raw strings.
+type FieldType int
+
+const (
+	FieldString FieldType = iota
+	FieldInt64
+	FieldFloat64
+	FieldBool
+	FieldTime
+)
+
+// TimeLayout is the format used to parse and format FieldTime columns.
+const TimeLayout = time.RFC3339
+
+func inferFieldType(value string) FieldType {
+	if value == "" {
+		return FieldString
+	}
+	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return FieldInt64
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return FieldFloat64
+	}
+	if _, err := strconv.ParseBool(value); err == nil {
+		return FieldBool
+	}
+	if _, err := time.Parse(TimeLayout, value); err == nil {
+		return FieldTime
+	}
+	return FieldString
+}
+
+// promoteFieldType widens a running column type to accommodate a new
+// value's type: int widens to float, anything else that disagrees
+// widens to string.
+func promoteFieldType(current, next FieldType) FieldType {
+	if current == next {
+		return current
+	}
+	if (current == FieldInt64 && next == FieldFloat64) || (current == FieldFloat64 && next == FieldInt64) {
+		return FieldFloat64
+	}
+	return FieldString
+}
+
+// inferSchema is the two-pass inference used over already-buffered rows:
+// it scans every value in every column and returns the narrowest
+// FieldType that fits all of them.
+func inferSchema(headers []string, rows [][]string) map[string]FieldType {
+	schema := make(map[string]FieldType, len(headers))
+	seen := make(map[string]bool, len(headers))
+	for _, row := range rows {
+		for j, cell := range row {
+			if j >= len(headers) {
+				continue
+			}
+			name := headers[j]
+			t := inferFieldType(cell)
+			if !seen[name] {
+				schema[name] = t
+				seen[name] = true
+			} else {
+				schema[name] = promoteFieldType(schema[name], t)
+			}
+		}
+	}
+	return schema
+}
+
+func parseCell(cell string, fieldType FieldType) interface{} {
+	switch fieldType {
+	case FieldInt64:
+		if v, err := strconv.ParseInt(cell, 10, 64); err == nil {
+			return v
+		}
+	case FieldFloat64:
+		if v, err := strconv.ParseFloat(cell, 64); err == nil {
+			return v
+		}
+	case FieldBool:
+		if v, err := strconv.ParseBool(cell); err == nil {
+			return v
+		}
+	case FieldTime:
+		if v, err := time.Parse(TimeLayout, cell); err == nil {
+			return v
+		}
+	}
+	return cell
+}
+
+// cellValue returns cell typed according to schema[fieldName], or cell
+// itself when schema is nil or has no entry for the column.
+func cellValue(cell string, fieldName string, schema map[string]FieldType) interface{} {
+	if schema == nil {
+		return cell
+	}
+	if fieldType, ok := schema[fieldName]; ok {
+		return parseCell(cell, fieldType)
+	}
+	return cell
+}
@@ -0,0 +1,61 @@
+package geocsv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInferTypes_TwoPass(t *testing.T) {
+	gc, err := Read(strings.NewReader("x,y,count,flag\n1,2,3,true\n4,5,3.5,false\n"), GeoCSVOptions{
+		XField:     "x",
+		YField:     "y",
+		InferTypes: true,
+	})
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	fc := gc.ToFeatureCollection()
+	if len(fc.Features) != 2 {
+		t.Fatalf("got %d features, want 2", len(fc.Features))
+	}
+
+	// count is int64 in row 0 but float64 in row 1, so the two-pass scan
+	// must promote the whole column to float64.
+	if _, ok := fc.Features[0].Properties["count"].(float64); !ok {
+		t.Errorf("count = %#v, want float64 (promoted)", fc.Features[0].Properties["count"])
+	}
+	if v, ok := fc.Features[0].Properties["flag"].(bool); !ok || v != true {
+		t.Errorf("flag = %#v, want bool(true)", fc.Features[0].Properties["flag"])
+	}
+}
+
+func TestSchema_ExplicitOverride(t *testing.T) {
+	gc, err := Read(strings.NewReader("x,y,code\n1,2,007\n"), GeoCSVOptions{
+		XField: "x",
+		YField: "y",
+		Schema: map[string]FieldType{"code": FieldString},
+	})
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	fc := gc.ToFeatureCollection()
+	if v, ok := fc.Features[0].Properties["code"].(string); !ok || v != "007" {
+		t.Errorf("code = %#v, want string(\"007\")", fc.Features[0].Properties["code"])
+	}
+}
+
+func TestPromoteFieldType(t *testing.T) {
+	cases := []struct {
+		current, next, want FieldType
+	}{
+		{FieldInt64, FieldInt64, FieldInt64},
+		{FieldInt64, FieldFloat64, FieldFloat64},
+		{FieldFloat64, FieldInt64, FieldFloat64},
+		{FieldInt64, FieldBool, FieldString},
+	}
+	for _, c := range cases {
+		if got := promoteFieldType(c.current, c.next); got != c.want {
+			t.Errorf("promoteFieldType(%v, %v) = %v, want %v", c.current, c.next, got, c.want)
+		}
+	}
+}
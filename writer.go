@@ -0,0 +1,145 @@
+package geocsv
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/flywave/go-geom"
+	"github.com/flywave/go-geom/wkb"
+	"github.com/flywave/go-geom/wkt"
+
+	"golang.org/x/text/transform"
+)
+
+// Writer writes features to a CSV file using the same column conventions
+// GeoCSV understands when reading: a WKT or WKB geometry column, or a pair
+// of X/Y coordinate columns for points.
+type Writer struct {
+	w       *csv.Writer
+	options GeoCSVOptions
+	fields  []string
+	wrote   bool
+}
+
+// NewWriter returns a Writer that serializes features to w according to
+// options. The column order is the geometry column(s) (WKTField and/or
+// WKBField, then XField/YField) followed by options.Fields, or the sorted
+// property keys of the first feature if Fields is empty.
+func NewWriter(w io.Writer, options GeoCSVOptions) *Writer {
+	if enc, ok := encoderFor(options.Encoding); ok && enc != nil {
+		w = transform.NewWriter(w, enc)
+	}
+	cw := csv.NewWriter(w)
+	if options.Delimiter != 0 {
+		cw.Comma = options.Delimiter
+	}
+	return &Writer{w: cw, options: options}
+}
+
+func (gw *Writer) geometryColumns() []string {
+	var cols []string
+	if gw.options.WKTField != "" {
+		cols = append(cols, gw.options.WKTField)
+	}
+	if gw.options.WKBField != "" {
+		cols = append(cols, gw.options.WKBField)
+	}
+	if gw.options.XField != "" && gw.options.YField != "" {
+		cols = append(cols, gw.options.XField, gw.options.YField)
+	}
+	return cols
+}
+
+func (gw *Writer) propertyFields(properties map[string]interface{}) []string {
+	if len(gw.options.Fields) > 0 {
+		return gw.options.Fields
+	}
+	fields := make([]string, 0, len(properties))
+	for name := range properties {
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// WriteFeature writes a single feature, emitting the header row first if
+// this is the first call.
+func (gw *Writer) WriteFeature(feature *geom.Feature) error {
+	if gw.fields == nil {
+		gw.fields = gw.propertyFields(feature.Properties)
+	}
+	if !gw.wrote {
+		gw.wrote = true
+		header := append(append([]string{}, gw.geometryColumns()...), gw.fields...)
+		if err := gw.w.Write(header); err != nil {
+			return err
+		}
+	}
+
+	record := make([]string, 0, len(gw.fields)+2)
+
+	if gw.options.WKTField != "" {
+		var buf bytes.Buffer
+		if err := wkt.EncodeWKT(geom.NewGeometryData(feature.Geometry), nil, &buf); err != nil {
+			return err
+		}
+		record = append(record, buf.String())
+	}
+	if gw.options.WKBField != "" {
+		var buf bytes.Buffer
+		if err := wkb.EncodeWKB(geom.NewGeometryData(feature.Geometry), nil, &buf); err != nil {
+			return err
+		}
+		record = append(record, hex.EncodeToString(buf.Bytes()))
+	}
+	if gw.options.XField != "" && gw.options.YField != "" {
+		if point, ok := feature.Geometry.(geom.Point); ok {
+			record = append(record,
+				strconv.FormatFloat(point.X(), 'f', -1, 64),
+				strconv.FormatFloat(point.Y(), 'f', -1, 64))
+		} else {
+			record = append(record, "", "")
+		}
+	}
+
+	for _, field := range gw.fields {
+		record = append(record, propertyToString(feature.Properties[field]))
+	}
+
+	return gw.w.Write(record)
+}
+
+// WriteFeatureCollection writes every feature in fc via WriteFeature.
+func (gw *Writer) WriteFeatureCollection(fc *geom.FeatureCollection) error {
+	for _, feature := range fc.Features {
+		if err := gw.WriteFeature(feature); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush flushes any buffered data to the underlying io.Writer.
+func (gw *Writer) Flush() error {
+	gw.w.Flush()
+	return gw.w.Error()
+}
+
+func propertyToString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		return v.Format(TimeLayout)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,66 @@
+package geocsv
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/flywave/go-geom"
+	"github.com/flywave/go-geom/general"
+)
+
+func TestWriter_WriteFeature_WKT(t *testing.T) {
+	feature := geom.NewFeature(general.NewPoint([]float64{2, 49}))
+	feature.Properties = map[string]interface{}{"name": "a"}
+
+	var buf strings.Builder
+	w := NewWriter(&buf, GeoCSVOptions{WKTField: "wkt", Fields: []string{"name"}})
+	if err := w.WriteFeature(feature); err != nil {
+		t.Fatalf("WriteFeature() error = %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	gc, err := Read(strings.NewReader(buf.String()), GeoCSVOptions{WKTField: "wkt"})
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	fc := gc.ToFeatureCollection()
+	if len(fc.Features) != 1 {
+		t.Fatalf("expected 1 feature, got %d", len(fc.Features))
+	}
+	point := fc.Features[0].Geometry.(geom.Point)
+	if point.X() != 2 || point.Y() != 49 {
+		t.Errorf("round-tripped coordinates wrong: got (%v, %v)", point.X(), point.Y())
+	}
+}
+
+func TestWriter_WriteFeature_WKB(t *testing.T) {
+	feature := geom.NewFeature(general.NewPoint([]float64{2, 49}))
+	feature.Properties = map[string]interface{}{"name": "a"}
+
+	var buf strings.Builder
+	w := NewWriter(&buf, GeoCSVOptions{WKBField: "wkb", Fields: []string{"name"}})
+	if err := w.WriteFeature(feature); err != nil {
+		t.Fatalf("WriteFeature() error = %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	gc, err := Read(strings.NewReader(buf.String()), GeoCSVOptions{
+		GeometryField:  "wkb",
+		GeometryFormat: FormatWKBHex,
+	})
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	fc := gc.ToFeatureCollection()
+	if len(fc.Features) != 1 {
+		t.Fatalf("expected 1 feature, got %d", len(fc.Features))
+	}
+	point := fc.Features[0].Geometry.(geom.Point)
+	if point.X() != 2 || point.Y() != 49 {
+		t.Errorf("round-tripped coordinates wrong: got (%v, %v)", point.X(), point.Y())
+	}
+}